@@ -0,0 +1,201 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	logcache "code.cloudfoundry.org/go-log-cache"
+	"code.cloudfoundry.org/go-log-cache/rpc/logcache_v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+)
+
+// MetaExporter runs an HTTP server that translates periodic client.Meta
+// polls into Prometheus gauges, so a log-cache-cli sidecar can be scraped
+// for dashboards/alerting the same way Loki/Cortex expose per-tenant
+// ingester stats.
+type MetaExporter struct {
+	*cobra.Command
+
+	conf    Config
+	timeout time.Duration
+
+	listenAddr     string
+	metricsPath    string
+	scrapeInterval time.Duration
+}
+
+type MetaExporterOption func(*MetaExporter)
+
+func WithMetaExporterTimeout(d time.Duration) MetaExporterOption {
+	return func(e *MetaExporter) {
+		e.timeout = d
+	}
+}
+
+func NewMetaExporter(conf Config, opts ...MetaExporterOption) *cobra.Command {
+	e := &MetaExporter{
+		conf:           conf,
+		timeout:        2 * time.Second,
+		listenAddr:     ":9187",
+		metricsPath:    "/metrics",
+		scrapeInterval: 15 * time.Second,
+	}
+	e.Command = e.command()
+
+	for _, o := range opts {
+		o(e)
+	}
+
+	return e.Command
+}
+
+func (e *MetaExporter) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "meta-exporter",
+		Short: "Serve log-cache meta information as Prometheus metrics",
+		RunE:  e.runE,
+		Args:  cobra.NoArgs,
+	}
+	cmd.Flags().StringVar(&e.listenAddr, "listen-addr", e.listenAddr, "address to serve /metrics on")
+	cmd.Flags().StringVar(&e.metricsPath, "metrics-path", e.metricsPath, "path to serve metrics on")
+	cmd.Flags().DurationVar(&e.scrapeInterval, "scrape-interval", e.scrapeInterval, "how often to poll log-cache for meta information")
+	return cmd
+}
+
+func (e *MetaExporter) runE(cmd *cobra.Command, args []string) error {
+	if e.scrapeInterval <= 0 {
+		return fmt.Errorf("--scrape-interval must be greater than 0, got %s", e.scrapeInterval)
+	}
+
+	client := logcache.NewClient(e.conf.Addr)
+	collector := newMetaCollector()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	ctx, cancel := waitForInterrupt(cmd.Context())
+	defer cancel()
+
+	go collector.pollLoop(ctx, client, e.timeout, e.scrapeInterval)
+
+	mux := http.NewServeMux()
+	mux.Handle(e.metricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	server := &http.Server{Addr: e.listenAddr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+// metaCollector implements prometheus.Collector. It reports whatever the
+// last background poll fetched rather than calling log-cache inline from
+// Collect, so a slow or down log-cache can't stall a Prometheus scrape.
+type metaCollector struct {
+	mu   sync.RWMutex
+	meta map[string]*logcache_v1.MetaInfo
+
+	count           *prometheus.Desc
+	expired         *prometheus.Desc
+	cacheDuration   *prometheus.Desc
+	newestTimestamp *prometheus.Desc
+	oldestTimestamp *prometheus.Desc
+}
+
+func newMetaCollector() *metaCollector {
+	labels := []string{"namespace", "type", "name"}
+	return &metaCollector{
+		count: prometheus.NewDesc(
+			"log_cache_source_envelope_count",
+			"Number of envelopes currently cached for a source.",
+			labels, nil,
+		),
+		expired: prometheus.NewDesc(
+			"log_cache_source_expired_total",
+			"Total number of envelopes that have expired from the cache for a source. Counter.",
+			labels, nil,
+		),
+		cacheDuration: prometheus.NewDesc(
+			"log_cache_source_cache_duration_seconds",
+			"Duration of cached data for a source, in seconds.",
+			labels, nil,
+		),
+		newestTimestamp: prometheus.NewDesc(
+			"log_cache_source_newest_timestamp_seconds",
+			"Unix timestamp of the newest envelope cached for a source.",
+			labels, nil,
+		),
+		oldestTimestamp: prometheus.NewDesc(
+			"log_cache_source_oldest_timestamp_seconds",
+			"Unix timestamp of the oldest envelope cached for a source.",
+			labels, nil,
+		),
+	}
+}
+
+func (c *metaCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.count
+	ch <- c.expired
+	ch <- c.cacheDuration
+	ch <- c.newestTimestamp
+	ch <- c.oldestTimestamp
+}
+
+func (c *metaCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	meta := c.meta
+	c.mu.RUnlock()
+
+	for _, r := range rows(meta) {
+		labelValues := []string{r.Namespace, r.ResourceType, r.ResourceName}
+
+		ch <- prometheus.MustNewConstMetric(c.count, prometheus.GaugeValue, float64(r.Count), labelValues...)
+		ch <- prometheus.MustNewConstMetric(c.expired, prometheus.CounterValue, float64(r.Expired), labelValues...)
+		ch <- prometheus.MustNewConstMetric(c.cacheDuration, prometheus.GaugeValue, r.Duration.Seconds(), labelValues...)
+		ch <- prometheus.MustNewConstMetric(c.newestTimestamp, prometheus.GaugeValue, float64(r.NewestTimestamp)/float64(time.Second), labelValues...)
+		ch <- prometheus.MustNewConstMetric(c.oldestTimestamp, prometheus.GaugeValue, float64(r.OldestTimestamp)/float64(time.Second), labelValues...)
+	}
+}
+
+// pollLoop refreshes the cached meta snapshot on scrapeInterval until ctx
+// is cancelled.
+func (c *metaCollector) pollLoop(ctx context.Context, client *logcache.Client, timeout, scrapeInterval time.Duration) {
+	poll := func() {
+		pollCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		meta, err := client.Meta(pollCtx)
+		if err != nil {
+			return
+		}
+
+		c.mu.Lock()
+		c.meta = meta
+		c.mu.Unlock()
+	}
+
+	poll()
+
+	ticker := time.NewTicker(scrapeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"testing"
+
+	"code.cloudfoundry.org/go-log-cache/rpc/logcache_v1"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func collectMetric(t *testing.T, desc *prometheus.Desc, meta map[string]*logcache_v1.MetaInfo) []*dto.Metric {
+	t.Helper()
+
+	c := newMetaCollector()
+	c.meta = meta
+
+	ch := make(chan prometheus.Metric, 64)
+	c.Collect(ch)
+	close(ch)
+
+	var out []*dto.Metric
+	for m := range ch {
+		if m.Desc().String() != desc.String() {
+			continue
+		}
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil {
+			t.Fatalf("unexpected error writing metric: %s", err)
+		}
+		out = append(out, &pb)
+	}
+	return out
+}
+
+func TestMetaCollectorEmitsExpiredAsACounter(t *testing.T) {
+	c := newMetaCollector()
+	metrics := collectMetric(t, c.expired, map[string]*logcache_v1.MetaInfo{
+		"prod/pod/app": {Count: 1, Expired: 42},
+	})
+
+	if len(metrics) != 1 {
+		t.Fatalf("expected 1 metric, got %d", len(metrics))
+	}
+	if metrics[0].Counter == nil {
+		t.Fatal("expected log_cache_source_expired_total to be emitted as a Prometheus counter")
+	}
+	if got := metrics[0].Counter.GetValue(); got != 42 {
+		t.Fatalf("expected counter value 42, got %v", got)
+	}
+}
+
+func TestMetaCollectorEmitsCountAsAGauge(t *testing.T) {
+	c := newMetaCollector()
+	metrics := collectMetric(t, c.count, map[string]*logcache_v1.MetaInfo{
+		"prod/pod/app": {Count: 7},
+	})
+
+	if len(metrics) != 1 {
+		t.Fatalf("expected 1 metric, got %d", len(metrics))
+	}
+	if metrics[0].Gauge == nil {
+		t.Fatal("expected log_cache_source_envelope_count to be emitted as a Prometheus gauge")
+	}
+}
+
+func TestMetaCollectorLabelsSourceIDParts(t *testing.T) {
+	c := newMetaCollector()
+	metrics := collectMetric(t, c.count, map[string]*logcache_v1.MetaInfo{
+		"prod/pod/app": {Count: 1},
+	})
+
+	labelValues := map[string]string{}
+	for _, l := range metrics[0].GetLabel() {
+		labelValues[l.GetName()] = l.GetValue()
+	}
+
+	want := map[string]string{"namespace": "prod", "type": "pod", "name": "app"}
+	for k, v := range want {
+		if labelValues[k] != v {
+			t.Fatalf("expected label %s=%s, got %s=%s", k, v, k, labelValues[k])
+		}
+	}
+}
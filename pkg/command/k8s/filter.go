@@ -0,0 +1,63 @@
+package k8s
+
+import (
+	"path/filepath"
+
+	"code.cloudfoundry.org/go-log-cache/rpc/logcache_v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// MetaFilter narrows a meta listing down to the source IDs an operator
+// cares about, applied before the map is turned into display rows.
+type MetaFilter struct {
+	Namespace    string
+	ResourceType string
+	Name         string // glob pattern, e.g. "nginx-*"
+	Selector     labels.Selector
+}
+
+// Apply returns the subset of meta whose sourceID matches every configured
+// field. Empty fields are treated as wildcards.
+func (f MetaFilter) Apply(meta map[string]*logcache_v1.MetaInfo) map[string]*logcache_v1.MetaInfo {
+	if f.Namespace == "" && f.ResourceType == "" && f.Name == "" && f.Selector == nil {
+		return meta
+	}
+
+	filtered := make(map[string]*logcache_v1.MetaInfo)
+	for sourceID, info := range meta {
+		name, resourceType, namespace := sourceParts(sourceID)
+
+		if f.Namespace != "" && f.Namespace != namespace {
+			continue
+		}
+
+		if f.ResourceType != "" && f.ResourceType != resourceType {
+			continue
+		}
+
+		if f.Name != "" {
+			if ok, err := filepath.Match(f.Name, name); err != nil || !ok {
+				continue
+			}
+		}
+
+		if f.Selector != nil && !f.Selector.Matches(sourceLabels(namespace, resourceType, name)) {
+			continue
+		}
+
+		filtered[sourceID] = info
+	}
+
+	return filtered
+}
+
+// sourceLabels exposes the parsed sourceID components as a label set so a
+// kubectl-style selector (e.g. "namespace=prod,type!=job") can be matched
+// against them.
+func sourceLabels(namespace, resourceType, name string) labels.Set {
+	return labels.Set{
+		"namespace": namespace,
+		"type":      resourceType,
+		"name":      name,
+	}
+}
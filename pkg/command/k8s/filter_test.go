@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"testing"
+
+	"code.cloudfoundry.org/go-log-cache/rpc/logcache_v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func testMeta() map[string]*logcache_v1.MetaInfo {
+	return map[string]*logcache_v1.MetaInfo{
+		"prod/pod/nginx-a":  {Count: 1},
+		"prod/pod/nginx-b":  {Count: 2},
+		"prod/job/migrator": {Count: 3},
+		"staging/pod/nginx": {Count: 4},
+	}
+}
+
+func TestMetaFilterNoFieldsSetReturnsEverything(t *testing.T) {
+	got := (MetaFilter{}).Apply(testMeta())
+	if len(got) != len(testMeta()) {
+		t.Fatalf("expected all %d sources, got %d", len(testMeta()), len(got))
+	}
+}
+
+func TestMetaFilterByNamespace(t *testing.T) {
+	got := MetaFilter{Namespace: "prod"}.Apply(testMeta())
+	if len(got) != 3 {
+		t.Fatalf("expected 3 sources in prod, got %d", len(got))
+	}
+	if _, ok := got["staging/pod/nginx"]; ok {
+		t.Fatal("expected staging source to be filtered out")
+	}
+}
+
+func TestMetaFilterByResourceType(t *testing.T) {
+	got := MetaFilter{ResourceType: "job"}.Apply(testMeta())
+	if len(got) != 1 {
+		t.Fatalf("expected 1 job source, got %d", len(got))
+	}
+	if _, ok := got["prod/job/migrator"]; !ok {
+		t.Fatal("expected migrator job source to survive the filter")
+	}
+}
+
+func TestMetaFilterByNameGlob(t *testing.T) {
+	got := MetaFilter{Name: "nginx-*"}.Apply(testMeta())
+	if len(got) != 2 {
+		t.Fatalf("expected 2 sources matching nginx-*, got %d", len(got))
+	}
+	if _, ok := got["staging/pod/nginx"]; ok {
+		t.Fatal("expected staging/pod/nginx not to match nginx-*")
+	}
+}
+
+func TestMetaFilterBySelector(t *testing.T) {
+	sel, err := labels.Parse("namespace=prod,type!=job")
+	if err != nil {
+		t.Fatalf("unexpected error parsing selector: %s", err)
+	}
+
+	got := MetaFilter{Selector: sel}.Apply(testMeta())
+	if len(got) != 2 {
+		t.Fatalf("expected 2 sources matching the selector, got %d", len(got))
+	}
+	for sourceID := range got {
+		if sourceID == "prod/job/migrator" || sourceID == "staging/pod/nginx" {
+			t.Fatalf("source %s should have been excluded by the selector", sourceID)
+		}
+	}
+}
+
+func TestSortRowsByCount(t *testing.T) {
+	rows := []row{
+		{ResourceName: "b", Count: 10},
+		{ResourceName: "a", Count: 1},
+		{ResourceName: "c", Count: 5},
+	}
+
+	sortRows(rows, "count", false)
+
+	want := []string{"a", "c", "b"}
+	for i, name := range want {
+		if rows[i].ResourceName != name {
+			t.Fatalf("index %d: expected %s, got %s", i, name, rows[i].ResourceName)
+		}
+	}
+}
+
+func TestSortRowsReverse(t *testing.T) {
+	rows := []row{
+		{ResourceName: "a", Count: 1},
+		{ResourceName: "b", Count: 10},
+	}
+
+	sortRows(rows, "count", true)
+
+	if rows[0].ResourceName != "b" || rows[1].ResourceName != "a" {
+		t.Fatalf("expected reverse count order, got %v", rows)
+	}
+}
+
+func TestSortRowsDefaultBreaksTiesByNamespaceThenName(t *testing.T) {
+	rows := []row{
+		{Namespace: "b", ResourceName: "a"},
+		{Namespace: "a", ResourceName: "b"},
+		{Namespace: "a", ResourceName: "a"},
+	}
+
+	sortRows(rows, "name", false)
+
+	want := [][2]string{{"a", "a"}, {"a", "b"}, {"b", "a"}}
+	for i, w := range want {
+		if rows[i].Namespace != w[0] || rows[i].ResourceName != w[1] {
+			t.Fatalf("index %d: expected %v, got {%s %s}", i, w, rows[i].Namespace, rows[i].ResourceName)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Formatter renders a set of rows to an io.Writer in a particular output
+// format. Implementations are selected via --output/-o.
+type Formatter interface {
+	Format(w io.Writer, noHeaders bool, rows []row) error
+}
+
+// formatterFor returns the Formatter for the given --output value. The bool
+// result is false if the format is not recognized.
+func formatterFor(output string) (Formatter, bool) {
+	switch {
+	case output == "" || output == "table":
+		return tableFormatter{}, true
+	case output == "wide":
+		return wideFormatter{}, true
+	case output == "json":
+		return jsonFormatter{}, true
+	case output == "yaml":
+		return yamlFormatter{}, true
+	case strings.HasPrefix(output, "jsonpath="):
+		return jsonpathFormatter{template: strings.TrimPrefix(output, "jsonpath=")}, true
+	default:
+		return nil, false
+	}
+}
+
+type tableFormatter struct{}
+
+func (tableFormatter) Format(w io.Writer, noHeaders bool, rows []row) error {
+	// Unlike the machine-readable formats, the human-facing table prints
+	// nothing at all for zero matching sources rather than a header with
+	// no rows under it.
+	if len(rows) == 0 {
+		return nil
+	}
+
+	headerArgs := []interface{}{
+		"RESOURCE",
+		"TYPE",
+		"NAMESPACE",
+		"COUNT",
+		"EXPIRED",
+		"CACHE DURATION",
+	}
+	headerFormat := "%s\t%s\t%s\t%s\t%s\t%s\n"
+	rowFormat := "%s\t%s\t%s\t%d\t%d\t%s\n"
+
+	tw := tabwriter.NewWriter(w, 0, 3, 3, ' ', 0)
+	if !noHeaders {
+		fmt.Fprintf(tw, headerFormat, headerArgs...)
+	}
+
+	for _, r := range rows {
+		fmt.Fprintf(
+			tw,
+			rowFormat,
+			r.ResourceName,
+			r.ResourceType,
+			r.Namespace,
+			r.Count,
+			r.Expired,
+			maxDuration(time.Second, r.Duration),
+		)
+	}
+
+	return tw.Flush()
+}
+
+type wideFormatter struct{}
+
+func (wideFormatter) Format(w io.Writer, noHeaders bool, rows []row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	headerArgs := []interface{}{
+		"RESOURCE",
+		"TYPE",
+		"NAMESPACE",
+		"COUNT",
+		"EXPIRED",
+		"CACHE DURATION",
+		"OLDEST",
+		"NEWEST",
+		"RATE",
+	}
+	headerFormat := "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
+	rowFormat := "%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%.2f/s\n"
+
+	tw := tabwriter.NewWriter(w, 0, 3, 3, ' ', 0)
+	if !noHeaders {
+		fmt.Fprintf(tw, headerFormat, headerArgs...)
+	}
+
+	for _, r := range rows {
+		d := maxDuration(time.Second, r.Duration)
+		fmt.Fprintf(
+			tw,
+			rowFormat,
+			r.ResourceName,
+			r.ResourceType,
+			r.Namespace,
+			r.Count,
+			r.Expired,
+			d,
+			time.Unix(0, r.OldestTimestamp).UTC().Format(time.RFC3339),
+			time.Unix(0, r.NewestTimestamp).UTC().Format(time.RFC3339),
+			float64(r.Count)/d.Seconds(),
+		)
+	}
+
+	return tw.Flush()
+}
+
+// metaJSON is the shape emitted by -o json and -o yaml. It carries the
+// logcache_v1.MetaInfo counts as reported, with timestamps as UTC RFC3339
+// strings (whole seconds) and the cache duration as a Go duration string
+// truncated to whole seconds.
+type metaJSON struct {
+	ResourceName    string `json:"resourceName"`
+	ResourceType    string `json:"resourceType"`
+	Namespace       string `json:"namespace"`
+	Count           int64  `json:"count"`
+	Expired         int64  `json:"expired"`
+	NewestTimestamp string `json:"newestTimestamp"`
+	OldestTimestamp string `json:"oldestTimestamp"`
+	CacheDuration   string `json:"cacheDuration"`
+}
+
+func toMetaJSON(rows []row) []metaJSON {
+	out := make([]metaJSON, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, metaJSON{
+			ResourceName:    r.ResourceName,
+			ResourceType:    r.ResourceType,
+			Namespace:       r.Namespace,
+			Count:           r.Count,
+			Expired:         r.Expired,
+			NewestTimestamp: time.Unix(0, r.NewestTimestamp).UTC().Format(time.RFC3339),
+			OldestTimestamp: time.Unix(0, r.OldestTimestamp).UTC().Format(time.RFC3339),
+			CacheDuration:   r.Duration.String(),
+		})
+	}
+	return out
+}
+
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(w io.Writer, noHeaders bool, rows []row) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(toMetaJSON(rows))
+}
+
+type yamlFormatter struct{}
+
+func (yamlFormatter) Format(w io.Writer, noHeaders bool, rows []row) error {
+	out, err := yaml.Marshal(toMetaJSON(rows))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// jsonpathFormatter evaluates a kubectl-style jsonpath template once per
+// row and writes the result on its own line.
+type jsonpathFormatter struct {
+	template string
+}
+
+func (f jsonpathFormatter) Format(w io.Writer, noHeaders bool, rows []row) error {
+	jp := jsonpath.New("meta")
+	if err := jp.Parse(f.template); err != nil {
+		return fmt.Errorf("invalid jsonpath template: %s", err)
+	}
+
+	for _, r := range toMetaJSON(rows) {
+		// jsonpath.Execute resolves fields by literal Go struct field name
+		// (reflect's FieldByName), not by `json:"..."` tag. Round-trip
+		// through encoding/json first, the same way kubectl does, so
+		// templates can use the same field names -o json/-o yaml print
+		// (resourceName, newestTimestamp, ...) instead of Go's PascalCase.
+		var generic interface{}
+		encoded, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(encoded, &generic); err != nil {
+			return err
+		}
+
+		if err := jp.Execute(w, generic); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
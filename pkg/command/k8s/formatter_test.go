@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testRows() []row {
+	return []row{
+		{
+			Count:           5,
+			Expired:         1,
+			Duration:        time.Second * 90,
+			NewestTimestamp: 2000000000,
+			OldestTimestamp: 1000000000,
+			ResourceName:    "app",
+			ResourceType:    "pod",
+			Namespace:       "some-namespace",
+		},
+	}
+}
+
+func TestFormatterForKnownFormats(t *testing.T) {
+	for _, output := range []string{"", "table", "wide", "json", "yaml", "jsonpath={.resourceName}"} {
+		if _, ok := formatterFor(output); !ok {
+			t.Errorf("expected %q to be a known output format", output)
+		}
+	}
+}
+
+func TestFormatterForUnknownFormat(t *testing.T) {
+	if _, ok := formatterFor("csv"); ok {
+		t.Fatal("expected csv to be an unknown output format")
+	}
+}
+
+func TestTableFormatterOmitsOutputForNoRows(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (tableFormatter{}).Format(&buf, false, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output for zero rows, got %q", buf.String())
+	}
+}
+
+func TestJSONFormatterEmitsEmptyArrayForNoRows(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (jsonFormatter{}).Format(&buf, false, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var decoded []metaJSON
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected valid JSON for zero rows, got %q: %s", buf.String(), err)
+	}
+	if len(decoded) != 0 {
+		t.Fatalf("expected an empty array, got %v", decoded)
+	}
+}
+
+func TestJSONFormatterUsesCamelCaseFieldNames(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (jsonFormatter{}).Format(&buf, false, testRows()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), `"resourceName"`) {
+		t.Fatalf("expected camelCase field names in JSON output, got %q", buf.String())
+	}
+}
+
+func TestJSONPathFormatterUsesDocumentedFieldNames(t *testing.T) {
+	var buf bytes.Buffer
+	f := jsonpathFormatter{template: "{.resourceName}"}
+	if err := f.Format(&buf, false, testRows()); err != nil {
+		t.Fatalf("expected jsonpath template using the documented JSON field name to succeed, got: %s", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "app" {
+		t.Fatalf("expected %q, got %q", "app", got)
+	}
+}
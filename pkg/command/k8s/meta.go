@@ -2,18 +2,17 @@ package k8s
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"os"
 	"sort"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	logcache "code.cloudfoundry.org/go-log-cache"
 	"code.cloudfoundry.org/go-log-cache/rpc/logcache_v1"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 type Meta struct {
@@ -22,6 +21,18 @@ type Meta struct {
 	conf      Config
 	timeout   time.Duration
 	noHeaders bool
+	output    string
+
+	namespace    string
+	resourceType string
+	name         string
+	selector     string
+
+	sortBy  string
+	reverse bool
+
+	watch    bool
+	interval time.Duration
 }
 
 type MetaOption func(*Meta)
@@ -38,10 +49,21 @@ func WithMetaNoHeaders() MetaOption {
 	}
 }
 
+// WithMetaOutputFormat sets the default --output value, overridden by the
+// user passing --output/-o explicitly.
+func WithMetaOutputFormat(output string) MetaOption {
+	return func(m *Meta) {
+		m.output = output
+	}
+}
+
 func NewMeta(conf Config, opts ...MetaOption) *cobra.Command {
 	m := &Meta{
-		conf:    conf,
-		timeout: 2 * time.Second,
+		conf:     conf,
+		timeout:  2 * time.Second,
+		output:   "table",
+		sortBy:   "name",
+		interval: 2 * time.Second,
 	}
 	m.Command = m.command()
 
@@ -59,11 +81,44 @@ func (m *Meta) command() *cobra.Command {
 		RunE:  m.runE,
 		Args:  cobra.NoArgs,
 	}
+	cmd.Flags().StringVarP(&m.output, "output", "o", m.output, "output format: table, wide, json, yaml, jsonpath=<template>")
+	cmd.Flags().StringVarP(&m.namespace, "namespace", "n", "", "only show sources in this namespace")
+	cmd.Flags().StringVar(&m.resourceType, "resource-type", "", "only show sources of this resource type")
+	cmd.Flags().StringVar(&m.name, "name", "", "only show sources whose name matches this glob pattern")
+	cmd.Flags().StringVar(&m.selector, "selector", "", "label selector on namespace/type/name, e.g. 'namespace=prod,type!=job'")
+	cmd.Flags().StringVar(&m.sortBy, "sort-by", m.sortBy, "sort by: name, count, expired, duration")
+	cmd.Flags().BoolVar(&m.reverse, "reverse", false, "reverse the sort order")
+	cmd.Flags().BoolVarP(&m.watch, "watch", "w", false, "poll and re-render on --interval instead of exiting after one listing")
+	cmd.Flags().DurationVar(&m.interval, "interval", m.interval, "poll interval when --watch is set")
 	return cmd
 }
 
 func (m *Meta) runE(cmd *cobra.Command, args []string) error {
+	formatter, ok := formatterFor(m.output)
+	if !ok {
+		return fmt.Errorf("unknown output format %q", m.output)
+	}
+
+	filter, err := m.filter()
+	if err != nil {
+		return err
+	}
+
 	client := logcache.NewClient(m.conf.Addr)
+
+	if m.watch {
+		if m.interval <= 0 {
+			return fmt.Errorf("--interval must be greater than 0, got %s", m.interval)
+		}
+		if m.output != "table" && m.output != "json" {
+			return fmt.Errorf("--watch only supports --output table or json, got %q", m.output)
+		}
+
+		ctx, cancel := waitForInterrupt(context.Background())
+		defer cancel()
+		return m.watchLoop(ctx, client, filter, cmd.OutOrStdout())
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
 	defer cancel()
 	meta, err := client.Meta(ctx)
@@ -73,45 +128,33 @@ func (m *Meta) runE(cmd *cobra.Command, args []string) error {
 		}
 		return err
 	}
-	if len(meta) == 0 {
-		return nil
-	}
+	meta = filter.Apply(meta)
 	rows := rows(meta)
+	sortRows(rows, m.sortBy, m.reverse)
 
-	headerArgs := []interface{}{
-		"RESOURCE",
-		"TYPE",
-		"NAMESPACE",
-		"COUNT",
-		"EXPIRED",
-		"CACHE DURATION",
+	if err := formatter.Format(cmd.OutOrStdout(), m.noHeaders, rows); err != nil {
+		return fmt.Errorf("error writing results: %s", err)
 	}
-	headerFormat := "%s\t%s\t%s\t%s\t%s\t%s\n"
-	rowFormat := "%s\t%s\t%s\t%d\t%d\t%s\n"
 
-	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 3, 3, ' ', 0)
-	if !m.noHeaders {
-		fmt.Fprintf(tw, headerFormat, headerArgs...)
-	}
+	return nil
+}
 
-	for _, r := range rows {
-		fmt.Fprintf(
-			tw,
-			rowFormat,
-			r.ResourceName,
-			r.ResourceType,
-			r.Namespace,
-			r.Count,
-			r.Expired,
-			maxDuration(time.Second, r.Duration),
-		)
+func (m *Meta) filter() (MetaFilter, error) {
+	f := MetaFilter{
+		Namespace:    m.namespace,
+		ResourceType: m.resourceType,
+		Name:         m.name,
 	}
 
-	if err = tw.Flush(); err != nil {
-		return errors.New("Error writing results")
+	if m.selector != "" {
+		sel, err := labels.Parse(m.selector)
+		if err != nil {
+			return MetaFilter{}, fmt.Errorf("invalid selector: %s", err)
+		}
+		f.Selector = sel
 	}
 
-	return nil
+	return f, nil
 }
 
 func maxDuration(a, b time.Duration) time.Duration {
@@ -134,6 +177,9 @@ type row struct {
 	Expired  int64
 	Duration time.Duration
 
+	NewestTimestamp int64
+	OldestTimestamp int64
+
 	ResourceName string
 	ResourceType string
 	Namespace    string
@@ -144,32 +190,55 @@ func rows(meta map[string]*logcache_v1.MetaInfo) []row {
 	for k, v := range meta {
 		resourceName, resourceType, namespace := sourceParts(k)
 		rows = append(rows, row{
-			Count:        v.Count,
-			Expired:      v.Expired,
-			Duration:     cacheDuration(v),
-			ResourceName: resourceName,
-			ResourceType: resourceType,
-			Namespace:    namespace,
+			Count:           v.Count,
+			Expired:         v.Expired,
+			Duration:        cacheDuration(v),
+			NewestTimestamp: v.NewestTimestamp,
+			OldestTimestamp: v.OldestTimestamp,
+			ResourceName:    resourceName,
+			ResourceType:    resourceType,
+			Namespace:       namespace,
 		})
 	}
-	sort.Slice(rows, func(i, j int) bool {
-		if rows[i].Namespace < rows[j].Namespace {
-			return true
-		}
-		if rows[i].Namespace > rows[j].Namespace {
-			return false
+	return rows
+}
+
+// sortRows orders rows in place by the given --sort-by key, falling back to
+// the namespace/name/type ordering the table used to hard-code whenever two
+// rows tie (or sortBy is "name"/unrecognized).
+func sortRows(rows []row, sortBy string, reverse bool) {
+	less := func(i, j int) bool {
+		switch sortBy {
+		case "count":
+			if rows[i].Count != rows[j].Count {
+				return rows[i].Count < rows[j].Count
+			}
+		case "expired":
+			if rows[i].Expired != rows[j].Expired {
+				return rows[i].Expired < rows[j].Expired
+			}
+		case "duration":
+			if rows[i].Duration != rows[j].Duration {
+				return rows[i].Duration < rows[j].Duration
+			}
 		}
 
-		if rows[i].ResourceName < rows[j].ResourceName {
-			return true
+		if rows[i].Namespace != rows[j].Namespace {
+			return rows[i].Namespace < rows[j].Namespace
 		}
-		if rows[i].ResourceName > rows[j].ResourceName {
-			return false
+
+		if rows[i].ResourceName != rows[j].ResourceName {
+			return rows[i].ResourceName < rows[j].ResourceName
 		}
 
 		return rows[i].ResourceType < rows[j].ResourceType
-	})
-	return rows
+	}
+
+	if reverse {
+		sort.Slice(rows, func(i, j int) bool { return less(j, i) })
+		return
+	}
+	sort.Slice(rows, less)
 }
 
 func cacheDuration(m *logcache_v1.MetaInfo) time.Duration {
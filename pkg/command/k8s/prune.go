@@ -0,0 +1,223 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	logcache "code.cloudfoundry.org/go-log-cache"
+	"code.cloudfoundry.org/go-log-cache/rpc/logcache_v1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// MetaClient is the subset of logcache.Client that Prune depends on. It
+// lets tests fake the Meta response against a mocked admin server instead
+// of standing up a full log-cache.
+type MetaClient interface {
+	Meta(ctx context.Context) (map[string]*logcache_v1.MetaInfo, error)
+}
+
+// SourceDeleter is the admin-endpoint contract a log-cache gateway must
+// implement for Prune to evict sources instead of merely listing them.
+// It's defined here, ahead of the server-side handler, so the CLI and its
+// tests can be written against the contract now and wired up to the real
+// gateway once that handler ships.
+type SourceDeleter interface {
+	DeleteSource(ctx context.Context, sourceID string) error
+}
+
+// httpSourceDeleter issues the delete over HTTP against a log-cache
+// gateway's admin endpoint, using the same conf.Addr as the
+// logcache.Client constructed alongside it in NewPrune.
+type httpSourceDeleter struct {
+	addr   string
+	client *http.Client
+}
+
+// newHTTPSourceDeleter normalizes addr the same way logcache.NewClient
+// expects it (a bare "host:port", no scheme) into the "http://host:port"
+// base URL net/http needs, so DeleteSource hits the same gateway Meta does
+// rather than standing up an independently-addressed client.
+func newHTTPSourceDeleter(addr string) *httpSourceDeleter {
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	return &httpSourceDeleter{addr: addr, client: http.DefaultClient}
+}
+
+func (d *httpSourceDeleter) DeleteSource(ctx context.Context, sourceID string) error {
+	endpoint := fmt.Sprintf("%s/v1/admin/sources/%s", d.addr, url.PathEscape(sourceID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("delete source %s: unexpected status %d", sourceID, resp.StatusCode)
+	}
+
+	return nil
+}
+
+type Prune struct {
+	*cobra.Command
+
+	conf    Config
+	timeout time.Duration
+
+	staleAfter   time.Duration
+	namespace    string
+	resourceType string
+	selector     string
+	dryRun       bool
+
+	client  MetaClient
+	deleter SourceDeleter
+}
+
+type PruneOption func(*Prune)
+
+func WithPruneTimeout(d time.Duration) PruneOption {
+	return func(p *Prune) {
+		p.timeout = d
+	}
+}
+
+// WithPruneSourceDeleter overrides the default HTTP deleter, e.g. to point
+// Prune at a mocked admin endpoint in tests.
+func WithPruneSourceDeleter(d SourceDeleter) PruneOption {
+	return func(p *Prune) {
+		p.deleter = d
+	}
+}
+
+// WithPruneMetaClient overrides the default logcache.Client, e.g. to feed
+// Prune a fixed Meta response in tests.
+func WithPruneMetaClient(c MetaClient) PruneOption {
+	return func(p *Prune) {
+		p.client = c
+	}
+}
+
+func NewPrune(conf Config, opts ...PruneOption) *cobra.Command {
+	p := &Prune{
+		conf:       conf,
+		timeout:    2 * time.Second,
+		staleAfter: 24 * time.Hour,
+		dryRun:     true,
+	}
+	p.Command = p.command()
+
+	for _, o := range opts {
+		o(p)
+	}
+
+	if p.client == nil {
+		p.client = logcache.NewClient(conf.Addr)
+	}
+
+	if p.deleter == nil {
+		p.deleter = newHTTPSourceDeleter(conf.Addr)
+	}
+
+	return p.Command
+}
+
+func (p *Prune) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove stale sources from log-cache",
+		RunE:  p.runE,
+		Args:  cobra.NoArgs,
+	}
+	cmd.Flags().DurationVar(&p.staleAfter, "stale-after", p.staleAfter, "prune sources whose newest envelope is older than this")
+	cmd.Flags().StringVarP(&p.namespace, "namespace", "n", "", "only consider sources in this namespace")
+	cmd.Flags().StringVar(&p.resourceType, "resource-type", "", "only consider sources of this resource type")
+	cmd.Flags().StringVar(&p.selector, "selector", "", "label selector on namespace/type/name, e.g. 'namespace=prod,type!=job'")
+	cmd.Flags().BoolVar(&p.dryRun, "dry-run", true, "only list the sources that would be pruned")
+	return cmd
+}
+
+func (p *Prune) runE(cmd *cobra.Command, args []string) error {
+	filter := MetaFilter{Namespace: p.namespace, ResourceType: p.resourceType}
+	if p.selector != "" {
+		sel, err := labels.Parse(p.selector)
+		if err != nil {
+			return fmt.Errorf("invalid selector: %s", err)
+		}
+		filter.Selector = sel
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	meta, err := p.client.Meta(ctx)
+	if err != nil {
+		return err
+	}
+	meta = filter.Apply(meta)
+
+	stale := staleRows(rows(meta), p.staleAfter)
+	if len(stale) == 0 {
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 3, 3, ' ', 0)
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", "RESOURCE", "TYPE", "NAMESPACE", "NEWEST")
+	for _, r := range stale {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.ResourceName, r.ResourceType, r.Namespace, time.Unix(0, r.NewestTimestamp).UTC().Format(time.RFC3339))
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.New("Error writing results")
+	}
+
+	if p.dryRun {
+		return nil
+	}
+
+	var failed []string
+	for _, r := range stale {
+		sourceID := r.Namespace + "/" + r.ResourceType + "/" + r.ResourceName
+		if err := p.deleter.DeleteSource(ctx, sourceID); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to prune %s: %s\n", sourceID, err)
+			failed = append(failed, sourceID)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to prune %d of %d sources: %s", len(failed), len(stale), strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// staleRows returns the rows whose newest envelope is older than
+// staleAfter relative to wall-clock time. A source whose own NewestTimestamp
+// happens to be the most recent in the result set is still stale if that
+// timestamp itself is old — e.g. a namespace that's been completely idle
+// for days has all its sources clustered together, none of them "recent"
+// relative to each other.
+func staleRows(rows []row, staleAfter time.Duration) []row {
+	cutoff := time.Now().Add(-staleAfter)
+
+	var stale []row
+	for _, r := range rows {
+		if time.Unix(0, r.NewestTimestamp).Before(cutoff) {
+			stale = append(stale, r)
+		}
+	}
+	return stale
+}
@@ -0,0 +1,112 @@
+package k8s_test
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"code.cloudfoundry.org/go-log-cache/rpc/logcache_v1"
+	"code.cloudfoundry.org/log-cache-cli/pkg/command/k8s"
+)
+
+// fakeMetaClient stands in for the mocked admin server: it returns a fixed
+// Meta response without talking to a real log-cache.
+type fakeMetaClient struct {
+	meta map[string]*logcache_v1.MetaInfo
+}
+
+func (f *fakeMetaClient) Meta(ctx context.Context) (map[string]*logcache_v1.MetaInfo, error) {
+	return f.meta, nil
+}
+
+// fakeDeleter is a mocked SourceDeleter standing in for the admin
+// endpoint, which doesn't exist on a real log-cache gateway yet.
+type fakeDeleter struct {
+	mu      sync.Mutex
+	deleted []string
+}
+
+func (f *fakeDeleter) DeleteSource(ctx context.Context, sourceID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, sourceID)
+	return nil
+}
+
+func staleMetaClient() *fakeMetaClient {
+	stale := time.Now().Add(-48 * time.Hour).UnixNano()
+	fresh := time.Now().UnixNano()
+	return &fakeMetaClient{
+		meta: map[string]*logcache_v1.MetaInfo{
+			"some-namespace/pod/stale-app":  {Count: 10, NewestTimestamp: stale, OldestTimestamp: stale},
+			"some-namespace/pod/active-app": {Count: 10, NewestTimestamp: fresh, OldestTimestamp: fresh},
+		},
+	}
+}
+
+func TestPruneDeletesStaleSourcesWhenNotDryRun(t *testing.T) {
+	deleter := &fakeDeleter{}
+	cmd := k8s.NewPrune(
+		k8s.Config{Addr: "127.0.0.1:0"},
+		k8s.WithPruneMetaClient(staleMetaClient()),
+		k8s.WithPruneSourceDeleter(deleter),
+	)
+	cmd.SetArgs([]string{"--stale-after", "24h", "--dry-run=false"})
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	deleter.mu.Lock()
+	defer deleter.mu.Unlock()
+	sort.Strings(deleter.deleted)
+	want := []string{"some-namespace/pod/stale-app"}
+	if len(deleter.deleted) != len(want) || deleter.deleted[0] != want[0] {
+		t.Fatalf("expected %v to be pruned, got %v", want, deleter.deleted)
+	}
+}
+
+func TestPruneDryRunDoesNotDelete(t *testing.T) {
+	deleter := &fakeDeleter{}
+	cmd := k8s.NewPrune(
+		k8s.Config{Addr: "127.0.0.1:0"},
+		k8s.WithPruneMetaClient(staleMetaClient()),
+		k8s.WithPruneSourceDeleter(deleter),
+	)
+	// --dry-run defaults to true.
+	cmd.SetArgs([]string{"--stale-after", "24h"})
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	deleter.mu.Lock()
+	defer deleter.mu.Unlock()
+	if len(deleter.deleted) != 0 {
+		t.Fatalf("expected no deletions in dry-run mode, got %v", deleter.deleted)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("stale-app")) {
+		t.Fatalf("expected dry-run output to list the stale source, got %q", out.String())
+	}
+}
+
+func TestPruneCommandDefaultsToDryRun(t *testing.T) {
+	cmd := k8s.NewPrune(k8s.Config{Addr: "127.0.0.1:0"})
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		t.Fatalf("unexpected error reading dry-run flag: %s", err)
+	}
+	if !dryRun {
+		t.Fatal("expected --dry-run to default to true")
+	}
+}
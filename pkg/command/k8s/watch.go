@@ -0,0 +1,188 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	logcache "code.cloudfoundry.org/go-log-cache"
+	"code.cloudfoundry.org/go-log-cache/rpc/logcache_v1"
+)
+
+// clearScreen is the ANSI sequence used to reset the terminal between
+// watch frames, mirroring what kubectl/top do for -w/--watch.
+const clearScreen = "\033[H\033[2J"
+
+// watchRow is a row plus the per-second deltas computed against the
+// previous poll. Raw Count is the number of envelopes cached right now and
+// raw Expired is a running total, so IngressRate is the change in Count per
+// second (net of envelopes that expired in the same interval) and
+// ExpiredRate is the change in Expired per second.
+type watchRow struct {
+	row
+
+	IngressRate float64
+	ExpiredRate float64
+}
+
+type watchRowJSON struct {
+	metaJSON
+	IngressRate float64 `json:"ingressRate"`
+	ExpiredRate float64 `json:"expiredRate"`
+}
+
+// watchLoop polls client.Meta on --interval, diffs each poll against the last
+// one, and re-renders until ctx is cancelled (SIGINT).
+func (m *Meta) watchLoop(ctx context.Context, client *logcache.Client, filter MetaFilter, out io.Writer) error {
+	ndjson := m.output == "json"
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	var (
+		prev     map[string]*logcache_v1.MetaInfo
+		prevTime time.Time
+	)
+
+	for {
+		pollCtx, cancel := context.WithTimeout(ctx, m.timeout)
+		meta, err := client.Meta(pollCtx)
+		cancel()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		meta = filter.Apply(meta)
+
+		now := time.Now()
+		wrows := deltaRows(meta, prev, now.Sub(prevTime))
+		sortWatchRows(wrows, m.sortBy, m.reverse)
+
+		if !ndjson {
+			fmt.Fprint(out, clearScreen)
+		}
+		if err := renderWatchRows(out, m.noHeaders, ndjson, wrows); err != nil {
+			return err
+		}
+
+		prev = meta
+		prevTime = now
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func deltaRows(meta, prev map[string]*logcache_v1.MetaInfo, elapsed time.Duration) []watchRow {
+	base := rows(meta)
+	wrows := make([]watchRow, 0, len(base))
+
+	for _, r := range base {
+		wr := watchRow{row: r}
+
+		sourceID := r.Namespace + "/" + r.ResourceType + "/" + r.ResourceName
+		if p, ok := prev[sourceID]; ok && elapsed > 0 {
+			wr.IngressRate = float64(r.Count-p.Count) / elapsed.Seconds()
+			wr.ExpiredRate = float64(r.Expired-p.Expired) / elapsed.Seconds()
+		}
+
+		wrows = append(wrows, wr)
+	}
+
+	return wrows
+}
+
+func sortWatchRows(wrows []watchRow, sortBy string, reverse bool) {
+	base := make([]row, len(wrows))
+	for i, wr := range wrows {
+		base[i] = wr.row
+	}
+	sortRows(base, sortBy, reverse)
+
+	byName := make(map[string]watchRow, len(wrows))
+	for _, wr := range wrows {
+		byName[wr.Namespace+"/"+wr.ResourceType+"/"+wr.ResourceName] = wr
+	}
+	for i, r := range base {
+		wrows[i] = byName[r.Namespace+"/"+r.ResourceType+"/"+r.ResourceName]
+	}
+}
+
+func renderWatchRows(w io.Writer, noHeaders, ndjson bool, wrows []watchRow) error {
+	if ndjson {
+		enc := json.NewEncoder(w)
+		for _, wr := range wrows {
+			mj := toMetaJSON([]row{wr.row})[0]
+			if err := enc.Encode(watchRowJSON{metaJSON: mj, IngressRate: wr.IngressRate, ExpiredRate: wr.ExpiredRate}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	headerArgs := []interface{}{
+		"RESOURCE",
+		"TYPE",
+		"NAMESPACE",
+		"COUNT",
+		"EXPIRED",
+		"INGRESS/s",
+		"EXPIRED/s",
+	}
+	headerFormat := "%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
+	rowFormat := "%s\t%s\t%s\t%d\t%d\t%.2f\t%.2f\n"
+
+	tw := tabwriter.NewWriter(w, 0, 3, 3, ' ', 0)
+	if !noHeaders {
+		fmt.Fprintf(tw, headerFormat, headerArgs...)
+	}
+
+	for _, wr := range wrows {
+		fmt.Fprintf(
+			tw,
+			rowFormat,
+			wr.ResourceName,
+			wr.ResourceType,
+			wr.Namespace,
+			wr.Count,
+			wr.Expired,
+			wr.IngressRate,
+			wr.ExpiredRate,
+		)
+	}
+
+	return tw.Flush()
+}
+
+// waitForInterrupt returns a context that is cancelled when the process
+// receives SIGINT, so the watch loop can exit cleanly instead of being
+// killed mid-frame.
+func waitForInterrupt(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(sig)
+	}()
+
+	return ctx, cancel
+}
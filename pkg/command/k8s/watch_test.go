@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"code.cloudfoundry.org/go-log-cache/rpc/logcache_v1"
+)
+
+func TestDeltaRowsComputesPerSecondRates(t *testing.T) {
+	prev := map[string]*logcache_v1.MetaInfo{
+		"prod/pod/app": {Count: 100, Expired: 10},
+	}
+	meta := map[string]*logcache_v1.MetaInfo{
+		"prod/pod/app": {Count: 150, Expired: 20},
+	}
+
+	wrows := deltaRows(meta, prev, 10*time.Second)
+
+	if len(wrows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(wrows))
+	}
+	if wrows[0].IngressRate != 5 {
+		t.Fatalf("expected ingress rate of 5/s, got %v", wrows[0].IngressRate)
+	}
+	if wrows[0].ExpiredRate != 1 {
+		t.Fatalf("expected expired rate of 1/s, got %v", wrows[0].ExpiredRate)
+	}
+}
+
+func TestDeltaRowsIsZeroForNewSources(t *testing.T) {
+	meta := map[string]*logcache_v1.MetaInfo{
+		"prod/pod/app": {Count: 150, Expired: 20},
+	}
+
+	wrows := deltaRows(meta, nil, 10*time.Second)
+
+	if wrows[0].IngressRate != 0 || wrows[0].ExpiredRate != 0 {
+		t.Fatalf("expected zero rates for a source with no previous poll, got %+v", wrows[0])
+	}
+}
+
+func TestRenderWatchRowsNDJSONEmitsOneObjectPerRow(t *testing.T) {
+	wrows := []watchRow{
+		{row: row{ResourceName: "a", Namespace: "prod", ResourceType: "pod"}, IngressRate: 1.5},
+		{row: row{ResourceName: "b", Namespace: "prod", ResourceType: "pod"}, IngressRate: 2.5},
+	}
+
+	var buf bytes.Buffer
+	if err := renderWatchRows(&buf, false, true, wrows); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	lines := bytes.Count(buf.Bytes(), []byte("\n"))
+	if lines != len(wrows) {
+		t.Fatalf("expected %d NDJSON lines, got %d in %q", len(wrows), lines, buf.String())
+	}
+}